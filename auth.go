@@ -0,0 +1,223 @@
+package firego
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// defaultTokenSkew is how long before a token's reported expiry it is
+// considered stale and eligible for refresh.
+const defaultTokenSkew = 60 * time.Second
+
+// TokenSource supplies Firebase auth tokens, refreshing them as
+// needed. Token returns the current token and the time at which it
+// expires; a zero expiry means the token never expires.
+type TokenSource interface {
+	Token() (token string, expiry time.Time, err error)
+}
+
+// StaticTokenSource is a TokenSource that always returns the same,
+// never-expiring token. It backs the legacy Auth method for callers
+// who manage their own token lifecycle.
+type StaticTokenSource string
+
+// Token implements TokenSource.
+func (s StaticTokenSource) Token() (string, time.Time, error) {
+	return string(s), time.Time{}, nil
+}
+
+// tokenRefresher caches a TokenSource's token, refreshing it once it
+// is within skew of expiring. Concurrent callers that observe a stale
+// cache block on the same mutex, so only one of them actually calls
+// Token(); the rest see the refreshed value once it's their turn.
+type tokenRefresher struct {
+	src  TokenSource
+	skew time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newTokenRefresher(src TokenSource, skew time.Duration) *tokenRefresher {
+	return &tokenRefresher{src: src, skew: skew}
+}
+
+func (r *tokenRefresher) get() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != "" && (r.expiry.IsZero() || time.Now().Before(r.expiry.Add(-r.skew))) {
+		return r.token, nil
+	}
+
+	token, expiry, err := r.src.Token()
+	if err != nil {
+		return "", err
+	}
+	r.token = token
+	r.expiry = expiry
+	return token, nil
+}
+
+// AuthWithTokenSource attaches ts to fb so that every request made
+// through fb, and through any Child() derived from it, carries a
+// fresh token from ts as the auth= query parameter. A token is
+// refreshed once it's within skew of its reported expiry; skew
+// defaults to defaultTokenSkew (60s) when no override is given, and
+// only the first override is used if more than one is passed.
+func (fb *Firebase) AuthWithTokenSource(ts TokenSource, skew ...time.Duration) {
+	s := defaultTokenSkew
+	if len(skew) > 0 {
+		s = skew[0]
+	}
+	fb.tokens = newTokenRefresher(ts, s)
+}
+
+const (
+	googleTokenURL        = "https://www.googleapis.com/oauth2/v4/token"
+	firebaseDatabaseScope = "https://www.googleapis.com/auth/firebase.database"
+	userinfoEmailScope    = "https://www.googleapis.com/auth/userinfo.email"
+)
+
+// ServiceAccountTokenSource is a TokenSource that signs a Google
+// service account's private key into a JWT and exchanges it at
+// Google's OAuth2 token endpoint for an access token, the same flow
+// used by the Firebase Admin SDK (firebase.google.com/go).
+type ServiceAccountTokenSource struct {
+	ClientEmail string
+	PrivateKey  *rsa.PrivateKey
+
+	// TokenURL overrides the OAuth2 token endpoint. Defaults to
+	// Google's production endpoint when empty; tests point it at a
+	// local stub.
+	TokenURL string
+
+	// Client is the HTTP client used to reach TokenURL. Defaults to
+	// http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// NewServiceAccountTokenSource parses a PEM-encoded RSA private key
+// (PKCS1 or PKCS8, as found in a Firebase service account JSON key
+// file's private_key field) and returns a TokenSource for clientEmail.
+func NewServiceAccountTokenSource(clientEmail string, privateKeyPEM []byte) (*ServiceAccountTokenSource, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("firego: no PEM block found in private key")
+	}
+
+	key, err := parseRSAPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceAccountTokenSource{ClientEmail: clientEmail, PrivateKey: key}, nil
+}
+
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("firego: parsing private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("firego: private key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+// Token implements TokenSource.
+func (s *ServiceAccountTokenSource) Token() (string, time.Time, error) {
+	assertion, err := s.signedJWT()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	resp, err := s.httpClient().PostForm(s.tokenURL(), url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", time.Time{}, fmt.Errorf("firego: token endpoint returned %s", resp.Status)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return body.AccessToken, time.Now().Add(time.Duration(body.ExpiresIn) * time.Second), nil
+}
+
+// signedJWT builds and RS256-signs the JWT assertion Google's token
+// endpoint expects for a service account's jwt-bearer grant.
+func (s *ServiceAccountTokenSource) signedJWT() (string, error) {
+	now := time.Now()
+
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.ClientEmail,
+		"scope": firebaseDatabaseScope + " " + userinfoEmailScope,
+		"aud":   s.tokenURL(),
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hashed := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (s *ServiceAccountTokenSource) tokenURL() string {
+	if s.TokenURL != "" {
+		return s.TokenURL
+	}
+	return googleTokenURL
+}
+
+func (s *ServiceAccountTokenSource) httpClient() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,190 @@
+package firego
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth_Legacy(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer("{}")
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+	fb.Auth("legacy-token")
+
+	var v interface{}
+	require.NoError(t, fb.Value(&v))
+	require.Len(t, server.receivedReqs, 1)
+	assert.Equal(t, "legacy-token", server.receivedReqs[0].URL.Query().Get("auth"))
+
+	fb.Unauth()
+	require.NoError(t, fb.Value(&v))
+	require.Len(t, server.receivedReqs, 2)
+	assert.Equal(t, "", server.receivedReqs[1].URL.Query().Get("auth"))
+}
+
+func TestAuthWithTokenSource_AppliesToken(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer("{}")
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+	fb.AuthWithTokenSource(StaticTokenSource("secret-token"))
+
+	var v interface{}
+	require.NoError(t, fb.Value(&v))
+	require.Len(t, server.receivedReqs, 1)
+	assert.Equal(t, "secret-token", server.receivedReqs[0].URL.Query().Get("auth"))
+}
+
+type countingTokenSource struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingTokenSource) Token() (string, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return fmt.Sprintf("token-%d", c.calls), time.Now().Add(time.Hour), nil
+}
+
+func TestAuthWithTokenSource_ConcurrentRefresh(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+	src := &countingTokenSource{}
+	fb.AuthWithTokenSource(src)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v interface{}
+			assert.NoError(t, fb.Value(&v))
+		}()
+	}
+	wg.Wait()
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	assert.Equal(t, 1, src.calls)
+}
+
+func TestAuthWithTokenSource_CustomSkew(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer("{}")
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+	src := &countingTokenSource{}
+
+	// countingTokenSource's tokens expire in 1 hour; a skew longer than
+	// that makes every cached token stale immediately, so every call
+	// should trigger its own refresh.
+	fb.AuthWithTokenSource(src, 2*time.Hour)
+
+	for i := 0; i < 3; i++ {
+		var v interface{}
+		require.NoError(t, fb.Value(&v))
+	}
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	assert.Equal(t, 3, src.calls)
+}
+
+func TestServiceAccountTokenSource(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	var gotAssertion string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.NoError(t, req.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", req.Form.Get("grant_type"))
+		gotAssertion = req.Form.Get("assertion")
+		fmt.Fprint(w, `{"access_token":"abc123","expires_in":3600,"token_type":"Bearer"}`)
+	}))
+	defer tokenServer.Close()
+
+	src, err := NewServiceAccountTokenSource("svc@example.iam.gserviceaccount.com", pemBytes)
+	require.NoError(t, err)
+	src.TokenURL = tokenServer.URL
+
+	token, expiry, err := src.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", token)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiry, 5*time.Second)
+
+	parts := strings.Split(gotAssertion, ".")
+	require.Len(t, parts, 3)
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	var claims struct {
+		Iss   string `json:"iss"`
+		Scope string `json:"scope"`
+		Aud   string `json:"aud"`
+	}
+	require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+	assert.Equal(t, "svc@example.iam.gserviceaccount.com", claims.Iss)
+	assert.Equal(t, tokenServer.URL, claims.Aud)
+	assert.Contains(t, claims.Scope, "https://www.googleapis.com/auth/firebase.database")
+	assert.Contains(t, claims.Scope, "https://www.googleapis.com/auth/userinfo.email")
+}
+
+func TestServiceAccountTokenSource_NonJSONErrorBody(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "<html>502 Bad Gateway</html>")
+	}))
+	defer tokenServer.Close()
+
+	src, err := NewServiceAccountTokenSource("svc@example.iam.gserviceaccount.com", pemBytes)
+	require.NoError(t, err)
+	src.TokenURL = tokenServer.URL
+
+	_, _, err = src.Token()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
@@ -0,0 +1,278 @@
+// Package firego is a REST client for the Firebase Realtime Database.
+package firego
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeoutDuration is the length of time any request will have to
+// establish a connection and receive response headers from Firebase
+// before an ErrTimeout is returned.
+var TimeoutDuration = 30 * time.Second
+
+// ErrTimeout is returned when a request's deadline (TimeoutDuration for
+// the non-Context methods, or the caller's context deadline) passes, or
+// the transport reports a timeout, before response headers arrive.
+type ErrTimeout struct {
+	error
+}
+
+// Firebase represents a location in the cloud.
+type Firebase struct {
+	url     string
+	params  url.Values
+	client  *http.Client
+	limiter *Limiter
+	tokens  *tokenRefresher
+	retry   *RetryPolicy
+
+	watchMu     sync.Mutex
+	watchCancel context.CancelFunc
+}
+
+// New creates a new Firebase reference rooted at root. If client is
+// nil, a client with firego's default timeout behavior is used.
+func New(root string, client *http.Client) *Firebase {
+	if client == nil {
+		client = newTimeoutClient()
+	}
+
+	return &Firebase{
+		url:    sanitizeURL(root),
+		params: url.Values{},
+		client: client,
+	}
+}
+
+// sanitizeURL ensures root has a scheme and no trailing slash.
+func sanitizeURL(root string) string {
+	if !strings.HasPrefix(root, "http://") && !strings.HasPrefix(root, "https://") {
+		root = "https://" + root
+	}
+	return strings.TrimSuffix(root, "/")
+}
+
+// newTimeoutClient returns an *http.Client whose Transport dials via a
+// plain context-aware dialer. The actual deadline for a given call
+// comes from the context passed to that call (see TimeoutDuration),
+// rather than being baked into the transport, so it's safe to share
+// across concurrent calls.
+func newTimeoutClient() *http.Client {
+	dialer := &net.Dialer{}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+		},
+	}
+}
+
+// Child returns a reference to the child node with the given name.
+// The child does not inherit the parent's query parameters.
+func (fb *Firebase) Child(child string) *Firebase {
+	return &Firebase{
+		url:     fb.url + "/" + child,
+		params:  url.Values{},
+		client:  fb.client,
+		limiter: fb.limiter,
+		tokens:  fb.tokens,
+		retry:   fb.retry,
+	}
+}
+
+// Value populates dest with the value at the current location. The
+// call is bounded by TimeoutDuration; use ValueContext to supply a
+// caller-controlled deadline instead.
+func (fb *Firebase) Value(dest interface{}) error {
+	ctx, cancel := fb.defaultContext()
+	defer cancel()
+	return fb.ValueContext(ctx, dest)
+}
+
+// ValueContext is the context-aware variant of Value. The request is
+// canceled as soon as ctx is done.
+func (fb *Firebase) ValueContext(ctx context.Context, dest interface{}) error {
+	return fb.doRequest(ctx, "GET", nil, dest)
+}
+
+// Set writes v to the current location. The call is bounded by
+// TimeoutDuration; use SetContext to supply a caller-controlled
+// deadline instead.
+func (fb *Firebase) Set(v interface{}) error {
+	ctx, cancel := fb.defaultContext()
+	defer cancel()
+	return fb.SetContext(ctx, v)
+}
+
+// SetContext is the context-aware variant of Set.
+func (fb *Firebase) SetContext(ctx context.Context, v interface{}) error {
+	return fb.doRequest(ctx, "PUT", v, nil)
+}
+
+// Push creates a new child under the current location with a unique
+// key and writes v to it. The call is bounded by TimeoutDuration; use
+// PushContext to supply a caller-controlled deadline instead.
+func (fb *Firebase) Push(v interface{}) (string, error) {
+	ctx, cancel := fb.defaultContext()
+	defer cancel()
+	return fb.PushContext(ctx, v)
+}
+
+// PushContext is the context-aware variant of Push.
+func (fb *Firebase) PushContext(ctx context.Context, v interface{}) (string, error) {
+	var resp struct {
+		Name string `json:"name"`
+	}
+	if err := fb.doRequest(ctx, "POST", v, &resp); err != nil {
+		return "", err
+	}
+	return resp.Name, nil
+}
+
+// Update performs a partial update of the current location with v.
+// The call is bounded by TimeoutDuration; use UpdateContext to supply
+// a caller-controlled deadline instead.
+func (fb *Firebase) Update(v interface{}) error {
+	ctx, cancel := fb.defaultContext()
+	defer cancel()
+	return fb.UpdateContext(ctx, v)
+}
+
+// UpdateContext is the context-aware variant of Update.
+func (fb *Firebase) UpdateContext(ctx context.Context, v interface{}) error {
+	return fb.doRequest(ctx, "PATCH", v, nil)
+}
+
+// Remove deletes the current location. The call is bounded by
+// TimeoutDuration; use RemoveContext to supply a caller-controlled
+// deadline instead.
+func (fb *Firebase) Remove() error {
+	ctx, cancel := fb.defaultContext()
+	defer cancel()
+	return fb.RemoveContext(ctx)
+}
+
+// RemoveContext is the context-aware variant of Remove.
+func (fb *Firebase) RemoveContext(ctx context.Context) error {
+	return fb.doRequest(ctx, "DELETE", nil, nil)
+}
+
+// defaultContext returns a background context carrying the
+// package-level TimeoutDuration, used by the legacy, non-Context
+// methods.
+func (fb *Firebase) defaultContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), TimeoutDuration)
+}
+
+// Auth sets the auth token used for subsequent requests. It's
+// equivalent to AuthWithTokenSource(StaticTokenSource(token)).
+func (fb *Firebase) Auth(token string) {
+	fb.AuthWithTokenSource(StaticTokenSource(token))
+}
+
+// Unauth removes the auth token from subsequent requests.
+func (fb *Firebase) Unauth() {
+	fb.tokens = nil
+}
+
+// rawURL returns the request URL, with authToken applied as the
+// auth= query parameter when non-empty.
+func (fb *Firebase) rawURL(authToken string) string {
+	values := fb.params
+	if authToken != "" {
+		values = url.Values{}
+		for k, v := range fb.params {
+			values[k] = v
+		}
+		values.Set("auth", authToken)
+	}
+
+	path := fb.url + "/.json"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	return path
+}
+
+// doRequest issues method against fb, retrying according to fb.retry
+// when set.
+func (fb *Firebase) doRequest(ctx context.Context, method string, body, dest interface{}) error {
+	if fb.retry == nil || fb.retry.MaxAttempts <= 1 {
+		return fb.doRequestOnce(ctx, method, body, dest)
+	}
+	return fb.doRequestWithRetry(ctx, method, body, dest)
+}
+
+// doRequestOnce issues method against fb exactly once.
+func (fb *Firebase) doRequestOnce(ctx context.Context, method string, body, dest interface{}) error {
+	if fb.limiter != nil {
+		if err := fb.limiter.acquire(ctx); err != nil {
+			return err
+		}
+		defer fb.limiter.release()
+	}
+
+	var authToken string
+	if fb.tokens != nil {
+		token, err := fb.tokens.get()
+		if err != nil {
+			return err
+		}
+		authToken = token
+	}
+
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, fb.rawURL(authToken), reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := fb.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return ErrTimeout{err}
+		}
+		if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+			return ErrTimeout{err}
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return errHTTPStatus{
+			status:     resp.Status,
+			code:       resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if dest == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
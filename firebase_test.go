@@ -1,10 +1,12 @@
 package firego
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -125,12 +127,6 @@ func TestTimeoutDuration_Headers(t *testing.T) {
 	err := fb.Value("")
 	assert.NotNil(t, err)
 	assert.IsType(t, ErrTimeout{}, err)
-
-	// ResponseHeaderTimeout should be TimeoutDuration less the time it took to dial, and should be positive
-	require.IsType(t, (*http.Transport)(nil), fb.client.Transport)
-	tr := fb.client.Transport.(*http.Transport)
-	assert.True(t, tr.ResponseHeaderTimeout < TimeoutDuration)
-	assert.True(t, tr.ResponseHeaderTimeout > 0)
 }
 
 func TestTimeoutDuration_Dial(t *testing.T) {
@@ -141,10 +137,60 @@ func TestTimeoutDuration_Dial(t *testing.T) {
 	err := fb.Value("")
 	assert.NotNil(t, err)
 	assert.IsType(t, ErrTimeout{}, err)
+}
+
+func TestTimeoutDuration_Concurrent(t *testing.T) {
+	// TimeoutDuration is shared package state, but each call derives its
+	// own context deadline from it instead of mutating a shared
+	// Transport.ResponseHeaderTimeout, so concurrent calls write no shared
+	// state; run with -race, this test flags any such write.
+	defer func(dur time.Duration) { TimeoutDuration = dur }(TimeoutDuration)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v interface{}
+			fb.Value(&v)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValueContext_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	fb := New(server.URL, nil)
 
-	// ResponseHeaderTimeout should be negative since the total duration was consumed when dialing
-	require.IsType(t, (*http.Transport)(nil), fb.client.Transport)
-	assert.True(t, fb.client.Transport.(*http.Transport).ResponseHeaderTimeout < 0)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		var v interface{}
+		done <- fb.ValueContext(ctx, &v)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NotNil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("ValueContext did not return promptly after cancel")
+	}
 }
 
 func TestShallow(t *testing.T) {
@@ -237,3 +283,83 @@ func TestIncludePriority(t *testing.T) {
 	req = server.receivedReqs[1]
 	assert.Equal(t, "", req.URL.Query().Encode())
 }
+
+func TestEqualTo(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"numeric", "3", equalToParam + "=3"},
+		{"quoted string", "\"user_id\"", equalToParam + "=%22user_id%22"},
+		{"boolean", "true", equalToParam + "=true"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer("")
+			defer server.Close()
+			fb := New(server.URL, nil)
+
+			fb.EqualTo(tt.value).Value("")
+			require.Len(t, server.receivedReqs, 1)
+
+			req := server.receivedReqs[0]
+			assert.Equal(t, tt.want, req.URL.Query().Encode())
+		})
+	}
+}
+
+func TestLimitToFirst(t *testing.T) {
+	t.Parallel()
+	var (
+		server = newTestServer("")
+		fb     = New(server.URL, nil)
+	)
+	defer server.Close()
+
+	fb.LimitToFirst(10).Value("")
+	require.Len(t, server.receivedReqs, 1)
+
+	req := server.receivedReqs[0]
+	assert.Equal(t, limitToFirstParam+"=10", req.URL.Query().Encode())
+
+	fb.LimitToFirst(0).Value("")
+	require.Len(t, server.receivedReqs, 2)
+
+	req = server.receivedReqs[1]
+	assert.Equal(t, "", req.URL.Query().Encode())
+}
+
+func TestLimitToLast(t *testing.T) {
+	t.Parallel()
+	var (
+		server = newTestServer("")
+		fb     = New(server.URL, nil)
+	)
+	defer server.Close()
+
+	fb.LimitToLast(10).Value("")
+	require.Len(t, server.receivedReqs, 1)
+
+	req := server.receivedReqs[0]
+	assert.Equal(t, limitToLastParam+"=10", req.URL.Query().Encode())
+
+	fb.LimitToLast(0).Value("")
+	require.Len(t, server.receivedReqs, 2)
+
+	req = server.receivedReqs[1]
+	assert.Equal(t, "", req.URL.Query().Encode())
+}
+
+func TestChild_QueryFiltersNotInherited(t *testing.T) {
+	t.Parallel()
+
+	parent := New(URL, nil)
+	parent.EqualTo("3").LimitToFirst(5).LimitToLast(5)
+
+	child := parent.Child("node")
+	assert.Len(t, child.params, 0)
+}
@@ -0,0 +1,88 @@
+package firego
+
+import (
+	"context"
+	"time"
+)
+
+// ErrQueueFull is returned when a Limiter's queue is already at
+// capacity and the request is rejected immediately rather than
+// waiting.
+type ErrQueueFull struct{}
+
+func (ErrQueueFull) Error() string { return "firego: request queue is full" }
+
+// ErrQueueTimeout is returned when a request waited in a Limiter's
+// queue longer than its configured timeout without acquiring a slot.
+type ErrQueueTimeout struct{}
+
+func (ErrQueueTimeout) Error() string { return "firego: timed out waiting for a free request slot" }
+
+// Limiter caps the number of in-flight HTTP requests issued by a
+// Firebase (and, since it's shared by reference with any of its
+// Child() references, every location derived from it). Excess
+// requests queue, in roughly FIFO order, until a slot frees up,
+// the queue fills, or they've waited longer than timeout.
+//
+// Attach a Limiter to a Firebase with WithLimiter.
+type Limiter struct {
+	slots   chan struct{}
+	queue   chan struct{}
+	timeout time.Duration
+}
+
+// NewLimiter returns a Limiter that allows at most maxConcurrency
+// requests in flight at once, queueing up to maxQueue more. A request
+// that arrives when the queue is already full fails immediately with
+// ErrQueueFull; one that waits in the queue longer than timeout fails
+// with ErrQueueTimeout.
+func NewLimiter(maxConcurrency, maxQueue int, timeout time.Duration) *Limiter {
+	return &Limiter{
+		slots:   make(chan struct{}, maxConcurrency),
+		queue:   make(chan struct{}, maxQueue),
+		timeout: timeout,
+	}
+}
+
+// acquire reserves a slot for ctx's request, blocking until one is
+// free, the queue is full, the wait exceeds l.timeout, or ctx is
+// done. On success the caller must call release when the request
+// completes.
+func (l *Limiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	select {
+	case l.queue <- struct{}{}:
+	default:
+		return ErrQueueFull{}
+	}
+	defer func() { <-l.queue }()
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrQueueTimeout{}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *Limiter) release() {
+	<-l.slots
+}
+
+// WithLimiter attaches l to fb so that requests made through fb - and
+// through any Child() derived from it, now or later - acquire a slot
+// from l before dialing. Passing nil detaches any existing limiter.
+func (fb *Firebase) WithLimiter(l *Limiter) *Firebase {
+	fb.limiter = l
+	return fb
+}
@@ -0,0 +1,168 @@
+package firego
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func slowServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(delay)
+		fmt.Fprint(w, "{}")
+	}))
+}
+
+func TestLimiter_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const maxConcurrency = 3
+
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithLimiter(NewLimiter(maxConcurrency, 50, time.Second))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v interface{}
+			assert.NoError(t, fb.Value(&v))
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, int(atomic.LoadInt32(&peak)), maxConcurrency)
+}
+
+func TestLimiter_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	handlerEntered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlerEntered <- struct{}{}
+		<-release
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+	defer close(release)
+
+	l := NewLimiter(1, 1, time.Second)
+	fb := New(server.URL, nil).WithLimiter(l)
+
+	// Occupy the single slot: wait for the handler signal so we know
+	// this request has actually reached (and is blocked in) the server,
+	// rather than guessing with a sleep.
+	go func() {
+		var v interface{}
+		fb.Value(&v)
+	}()
+	<-handlerEntered
+
+	// Occupy the single queue slot. It never reaches the handler, since
+	// the slot above is held until release is closed, so there's no
+	// signal to wait on for it directly; instead poll the limiter's
+	// queue until the second request has registered there.
+	go func() {
+		var v interface{}
+		fb.Value(&v)
+	}()
+	require.Eventually(t, func() bool {
+		return len(l.queue) == 1
+	}, time.Second, time.Millisecond, "second request never reached the queue")
+
+	var v interface{}
+	err := fb.Value(&v)
+	assert.IsType(t, ErrQueueFull{}, err)
+}
+
+func TestLimiter_QueueTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := slowServer(100 * time.Millisecond)
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithLimiter(NewLimiter(1, 1, 10*time.Millisecond))
+
+	// Occupy the single slot so the next caller has to queue and wait
+	// out the limiter's timeout.
+	go func() {
+		var v interface{}
+		fb.Value(&v)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	var v interface{}
+	err := fb.Value(&v)
+	assert.IsType(t, ErrQueueTimeout{}, err)
+}
+
+func TestLimiter_Child(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(1, 1, time.Second)
+	parent := New(URL, nil).WithLimiter(l)
+	child := parent.Child("node")
+
+	assert.Same(t, l, child.limiter)
+}
+
+func BenchmarkLimiter_BoundedConcurrency(b *testing.B) {
+	const maxConcurrency = 4
+
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithLimiter(NewLimiter(maxConcurrency, b.N, time.Minute))
+
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v interface{}
+			fb.Value(&v)
+		}()
+	}
+	wg.Wait()
+	b.StopTimer()
+
+	if int(atomic.LoadInt32(&peak)) > maxConcurrency {
+		b.Fatalf("observed %d concurrent requests, want <= %d", peak, maxConcurrency)
+	}
+}
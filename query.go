@@ -0,0 +1,103 @@
+package firego
+
+import "strconv"
+
+const (
+	shallowParam      = "shallow"
+	orderByParam      = "orderBy"
+	startAtParam      = "startAt"
+	endAtParam        = "endAt"
+	formatParam       = "format"
+	formatVal         = "export"
+	equalToParam      = "equalTo"
+	limitToFirstParam = "limitToFirst"
+	limitToLastParam  = "limitToLast"
+)
+
+// Shallow limits the depth of the data returned. If true, data at the
+// current location is returned with immediate children truncated to
+// true.
+func (fb *Firebase) Shallow(v bool) *Firebase {
+	if v {
+		fb.params.Set(shallowParam, "true")
+	} else {
+		fb.params.Del(shallowParam)
+	}
+	return fb
+}
+
+// OrderBy selects a child key or a well-known path ($key, $value,
+// $priority) to order query results by.
+func (fb *Firebase) OrderBy(value string) *Firebase {
+	if value == "" {
+		fb.params.Del(orderByParam)
+	} else {
+		fb.params.Set(orderByParam, value)
+	}
+	return fb
+}
+
+// StartAt restricts results to those greater than or equal to value,
+// using the ordering set by OrderBy.
+func (fb *Firebase) StartAt(value string) *Firebase {
+	if value == "" {
+		fb.params.Del(startAtParam)
+	} else {
+		fb.params.Set(startAtParam, value)
+	}
+	return fb
+}
+
+// EndAt restricts results to those less than or equal to value, using
+// the ordering set by OrderBy.
+func (fb *Firebase) EndAt(value string) *Firebase {
+	if value == "" {
+		fb.params.Del(endAtParam)
+	} else {
+		fb.params.Set(endAtParam, value)
+	}
+	return fb
+}
+
+// IncludePriority includes each node's priority in its data.
+func (fb *Firebase) IncludePriority(v bool) *Firebase {
+	if v {
+		fb.params.Set(formatParam, formatVal)
+	} else {
+		fb.params.Del(formatParam)
+	}
+	return fb
+}
+
+// EqualTo restricts results to those exactly matching value, using
+// the ordering set by OrderBy.
+func (fb *Firebase) EqualTo(value string) *Firebase {
+	if value == "" {
+		fb.params.Del(equalToParam)
+	} else {
+		fb.params.Set(equalToParam, value)
+	}
+	return fb
+}
+
+// LimitToFirst restricts results to at most the first n items, using
+// the ordering set by OrderBy. Passing 0 clears the limit.
+func (fb *Firebase) LimitToFirst(n int) *Firebase {
+	if n == 0 {
+		fb.params.Del(limitToFirstParam)
+	} else {
+		fb.params.Set(limitToFirstParam, strconv.Itoa(n))
+	}
+	return fb
+}
+
+// LimitToLast restricts results to at most the last n items, using
+// the ordering set by OrderBy. Passing 0 clears the limit.
+func (fb *Firebase) LimitToLast(n int) *Firebase {
+	if n == 0 {
+		fb.params.Del(limitToLastParam)
+	} else {
+		fb.params.Set(limitToLastParam, strconv.Itoa(n))
+	}
+	return fb
+}
@@ -0,0 +1,142 @@
+package firego
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures automatic retry of failed requests. Attach
+// one to a Firebase with WithRetry.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a request is
+	// attempted, including the first. A value <= 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the base delay before the first retry; it
+	// doubles on each subsequent attempt.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff delay between retries.
+	// A wait taken from a server's Retry-After header is not capped.
+	MaxBackoff time.Duration
+
+	// Jitter, when true, sleeps a random duration in [0, backoff]
+	// (full jitter) instead of the raw exponential backoff.
+	Jitter bool
+}
+
+// WithRetry attaches policy to fb so that failed requests made
+// through fb, and through any Child() derived from it, are
+// automatically re-issued. Retries fire on ErrTimeout, a connection
+// reset, and HTTP 5xx/429 responses, honoring a Retry-After header
+// when the server sends one. Push is non-idempotent and is only
+// retried when the server never accepted the original request (a
+// dial or pre-headers timeout); Value, Set, Update, and Remove are
+// always safe to retry.
+func (fb *Firebase) WithRetry(policy RetryPolicy) *Firebase {
+	fb.retry = &policy
+	return fb
+}
+
+// errHTTPStatus is returned by doRequestOnce when the server responds
+// with a status code indicating failure.
+type errHTTPStatus struct {
+	status     string
+	code       int
+	retryAfter time.Duration
+}
+
+func (e errHTTPStatus) Error() string {
+	return "firego: server returned " + e.status
+}
+
+// parseRetryAfter parses an HTTP Retry-After header, which is either
+// a number of seconds or an HTTP-date. It returns 0 if h is empty or
+// unparseable.
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// classifyRetry inspects an error from doRequestOnce and reports
+// whether it's worth retrying and whether the server actually
+// received and processed the request (as opposed to the failure
+// happening during dial or before response headers arrived).
+func classifyRetry(err error) (retriable, reachedServer bool, retryAfter time.Duration) {
+	var herr errHTTPStatus
+	if errors.As(err, &herr) {
+		reachedServer = true
+		retriable = herr.code == http.StatusTooManyRequests || herr.code >= 500
+		retryAfter = herr.retryAfter
+		return
+	}
+
+	if _, ok := err.(ErrTimeout); ok {
+		return true, false, 0
+	}
+
+	if strings.Contains(err.Error(), "connection reset") {
+		return true, false, 0
+	}
+
+	return false, false, 0
+}
+
+// doRequestWithRetry is the retrying variant of doRequestOnce, used
+// whenever fb.retry is set.
+func (fb *Firebase) doRequestWithRetry(ctx context.Context, method string, body, dest interface{}) error {
+	idempotent := method != http.MethodPost
+
+	backoff := fb.retry.InitialBackoff
+	var lastErr error
+
+	for attempt := 0; attempt < fb.retry.MaxAttempts; attempt++ {
+		err := fb.doRequestOnce(ctx, method, body, dest)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		retriable, reachedServer, retryAfter := classifyRetry(err)
+		_, timedOut := err.(ErrTimeout)
+		if !retriable || (!idempotent && (reachedServer || !timedOut)) {
+			return err
+		}
+		if attempt == fb.retry.MaxAttempts-1 {
+			return err
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			wait = backoff
+			if wait > fb.retry.MaxBackoff {
+				wait = fb.retry.MaxBackoff
+			}
+			if fb.retry.Jitter {
+				wait = time.Duration(rand.Int63n(int64(wait) + 1))
+			}
+			backoff *= 2
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	return lastErr
+}
@@ -0,0 +1,141 @@
+package firego
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry_RetriesOn503(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		reqTimes  []time.Time
+		failsLeft = 2
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		reqTimes = append(reqTimes, time.Now())
+		fail := failsLeft > 0
+		if fail {
+			failsLeft--
+		}
+		mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithRetry(RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	})
+
+	var v interface{}
+	require.NoError(t, fb.Value(&v))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reqTimes, 3)
+
+	// Compare each gap against its own deterministic lower bound (10ms,
+	// then 20ms after doubling) rather than against each other: under
+	// scheduler contention both gaps inflate by varying amounts, so
+	// gap2 > gap1 can fail even though the backoff is in fact doubling.
+	gap1 := reqTimes[1].Sub(reqTimes[0])
+	gap2 := reqTimes[2].Sub(reqTimes[1])
+	assert.True(t, gap1 >= 9*time.Millisecond, "gap1=%s", gap1)
+	assert.True(t, gap2 >= 18*time.Millisecond, "gap2=%s", gap2)
+}
+
+func TestWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithRetry(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+
+	var v interface{}
+	err := fb.Value(&v)
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestWithRetry_PushNotRetriedAfterServerResponse(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithRetry(RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+
+	_, err := fb.Push(map[string]string{"a": "b"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestWithRetry_HonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		reqTimes []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		reqTimes = append(reqTimes, time.Now())
+		n := len(reqTimes)
+		mu.Unlock()
+
+		if n == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil).WithRetry(RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})
+
+	var v interface{}
+	require.NoError(t, fb.Value(&v))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reqTimes, 2)
+	assert.True(t, reqTimes[1].Sub(reqTimes[0]) >= 900*time.Millisecond)
+}
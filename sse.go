@@ -0,0 +1,73 @@
+package firego
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sseEvent is a single dispatched Server-Sent Event.
+type sseEvent struct {
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// sseReader parses a Server-Sent Events stream per the EventSource
+// spec (https://html.spec.whatwg.org/multipage/server-sent-events.html),
+// accumulating "event:"/"data:"/"retry:" fields until a blank line
+// dispatches them.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next dispatched event, blocking until a field
+// block is terminated by a blank line or the stream ends. It returns
+// the underlying read error (io.EOF on a clean close) once no further
+// events remain.
+func (s *sseReader) next() (sseEvent, error) {
+	var (
+		ev        sseEvent
+		dataLines []string
+		haveField bool
+	)
+
+	for {
+		line, err := s.r.ReadString('\n')
+		field := strings.TrimRight(line, "\r\n")
+
+		if field != "" {
+			haveField = true
+			switch {
+			case strings.HasPrefix(field, "event:"):
+				ev.Event = strings.TrimSpace(strings.TrimPrefix(field, "event:"))
+			case strings.HasPrefix(field, "data:"):
+				dataLines = append(dataLines, strings.TrimPrefix(strings.TrimPrefix(field, "data:"), " "))
+			case strings.HasPrefix(field, "retry:"):
+				if ms, convErr := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(field, "retry:"))); convErr == nil {
+					ev.Retry = time.Duration(ms) * time.Millisecond
+				}
+			case strings.HasPrefix(field, ":"):
+				// comment line, ignore
+			}
+		} else if line != "" && haveField {
+			// a blank line (bare "\n" or "\r\n") dispatches the event
+			ev.Data = strings.Join(dataLines, "\n")
+			return ev, nil
+		}
+
+		if err != nil {
+			if haveField {
+				ev.Data = strings.Join(dataLines, "\n")
+				return ev, nil
+			}
+			return sseEvent{}, err
+		}
+	}
+}
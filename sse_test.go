@@ -0,0 +1,112 @@
+package firego
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkReader dribbles src out a few bytes at a time, to exercise the
+// parser's handling of a frame split across reads the way a TCP
+// stream can split it across packets.
+type chunkReader struct {
+	src []byte
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(c.src) == 0 {
+		return 0, io.EOF
+	}
+	n := 3
+	if n > len(c.src) {
+		n = len(c.src)
+	}
+	if n > len(p) {
+		n = len(p)
+	}
+	copy(p, c.src[:n])
+	c.src = c.src[n:]
+	return n, nil
+}
+
+func TestSSEReader(t *testing.T) {
+	testCases := []struct {
+		name   string
+		stream string
+		want   []sseEvent
+	}{
+		{
+			name:   "single line data",
+			stream: "event: put\ndata: {\"path\":\"/\",\"data\":1}\n\n",
+			want: []sseEvent{
+				{Event: "put", Data: `{"path":"/","data":1}`},
+			},
+		},
+		{
+			name:   "multi-line data is concatenated with newlines",
+			stream: "event: put\ndata: line one\ndata: line two\n\n",
+			want: []sseEvent{
+				{Event: "put", Data: "line one\nline two"},
+			},
+		},
+		{
+			name:   "crlf line endings",
+			stream: "event: put\r\ndata: hello\r\n\r\n",
+			want: []sseEvent{
+				{Event: "put", Data: "hello"},
+			},
+		},
+		{
+			name:   "retry field is parsed",
+			stream: "retry: 1500\nevent: put\ndata: x\n\n",
+			want: []sseEvent{
+				{Event: "put", Data: "x", Retry: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:   "comment lines are ignored",
+			stream: ": this is a comment\nevent: put\ndata: x\n\n",
+			want: []sseEvent{
+				{Event: "put", Data: "x"},
+			},
+		},
+		{
+			name:   "multiple events in one stream",
+			stream: "event: put\ndata: 1\n\nevent: patch\ndata: 2\n\n",
+			want: []sseEvent{
+				{Event: "put", Data: "1"},
+				{Event: "patch", Data: "2"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := newSSEReader(strings.NewReader(tt.stream))
+			for _, want := range tt.want {
+				got, err := sr.next()
+				require.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+			_, err := sr.next()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestSSEReader_SplitAcrossReads(t *testing.T) {
+	stream := "event: put\ndata: {\"path\":\"/a\",\"data\":\"split across several small reads\"}\n\n"
+	sr := newSSEReader(&chunkReader{src: []byte(stream)})
+
+	got, err := sr.next()
+	require.NoError(t, err)
+	assert.Equal(t, "put", got.Event)
+	assert.Equal(t, `{"path":"/a","data":"split across several small reads"}`, got.Data)
+
+	_, err = sr.next()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,171 @@
+package firego
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Event represents a change notification delivered by Watch.
+type Event struct {
+	Type string
+	Path string
+	Data interface{}
+
+	// Err is set on the final event of a watch that was ended by the
+	// server (a "cancel" or "auth_revoked" SSE event), and is nil on
+	// every other event.
+	Err error
+}
+
+// ErrWatchCancelled is delivered as Event.Err when the server revokes
+// a watch via a "cancel" or "auth_revoked" SSE event. Unlike a
+// connection error, it is terminal: Watch does not reconnect.
+type ErrWatchCancelled struct {
+	// Reason is the SSE event name that ended the watch: "cancel" or
+	// "auth_revoked".
+	Reason string
+}
+
+func (e ErrWatchCancelled) Error() string {
+	return fmt.Sprintf("firego: watch cancelled by server: %s", e.Reason)
+}
+
+const (
+	initialWatchBackoff = 250 * time.Millisecond
+	maxWatchBackoff     = 30 * time.Second
+)
+
+// Watch streams change notifications for the current location to
+// notifications, reconnecting automatically with exponential backoff
+// until StopWatching is called or the server ends the watch.
+// notifications is closed when the watch ends.
+func (fb *Firebase) Watch(notifications chan Event) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	fb.setWatchCancel(cancel)
+	return fb.WatchContext(ctx, notifications)
+}
+
+// WatchContext is the context-aware variant of Watch. The watch stops
+// and notifications is closed as soon as ctx is done.
+func (fb *Firebase) WatchContext(ctx context.Context, notifications chan Event) error {
+	go fb.watchLoop(ctx, notifications)
+	return nil
+}
+
+// StopWatching stops the in-flight Watch started on fb, if any.
+func (fb *Firebase) StopWatching() {
+	fb.watchMu.Lock()
+	cancel := fb.watchCancel
+	fb.watchMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (fb *Firebase) setWatchCancel(cancel context.CancelFunc) {
+	fb.watchMu.Lock()
+	fb.watchCancel = cancel
+	fb.watchMu.Unlock()
+}
+
+func (fb *Firebase) watchLoop(ctx context.Context, notifications chan Event) {
+	defer close(notifications)
+
+	backoff := initialWatchBackoff
+	for {
+		retry, done := fb.watchOnce(ctx, notifications)
+		if done || ctx.Err() != nil {
+			return
+		}
+
+		wait := backoff
+		if retry > 0 {
+			wait = retry
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > maxWatchBackoff {
+			backoff = maxWatchBackoff
+		}
+	}
+}
+
+// watchOnce opens a single SSE connection and dispatches events until
+// the connection drops or the server ends the watch. It returns the
+// SSE retry hint from the last event that carried one (0 if none did)
+// and whether the watch has ended permanently.
+func (fb *Firebase) watchOnce(ctx context.Context, notifications chan Event) (retry time.Duration, done bool) {
+	var authToken string
+	if fb.tokens != nil {
+		token, err := fb.tokens.get()
+		if err != nil {
+			return 0, false
+		}
+		authToken = token
+	}
+
+	req, err := http.NewRequest("GET", fb.rawURL(authToken), nil)
+	if err != nil {
+		return 0, false
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := fb.client.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		ioutil.ReadAll(resp.Body)
+		return 0, false
+	}
+
+	sr := newSSEReader(resp.Body)
+	for {
+		ev, err := sr.next()
+		if err != nil {
+			return retry, false
+		}
+
+		if ev.Retry > 0 {
+			retry = ev.Retry
+		}
+
+		switch ev.Event {
+		case "keep-alive":
+			continue
+
+		case "cancel", "auth_revoked":
+			select {
+			case notifications <- Event{Type: ev.Event, Err: ErrWatchCancelled{Reason: ev.Event}}:
+			case <-ctx.Done():
+			}
+			return retry, true
+
+		case "put", "patch":
+			var payload struct {
+				Path string      `json:"path"`
+				Data interface{} `json:"data"`
+			}
+			if err := json.Unmarshal([]byte(ev.Data), &payload); err != nil {
+				return retry, false
+			}
+			select {
+			case notifications <- Event{Type: ev.Event, Path: payload.Path, Data: payload.Data}:
+			case <-ctx.Done():
+				return retry, true
+			}
+		}
+	}
+}
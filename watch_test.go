@@ -0,0 +1,72 @@
+package firego
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "text/event-stream", req.Header.Get("Accept"))
+
+		flusher := w.(http.Flusher)
+		frames := []string{
+			"event: put\n",
+			"data: {\"path\":\"/\",\"data\":1}\n\n",
+			"event: keep-alive\ndata: null\n\n",
+			"event: patch\ndata: {\"path\":\"/a\",\"data\":2}\n\n",
+		}
+		for _, frame := range frames {
+			fmt.Fprint(w, frame)
+			flusher.Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+	notifications := make(chan Event)
+	require.NoError(t, fb.Watch(notifications))
+
+	ev := <-notifications
+	assert.Equal(t, Event{Type: "put", Path: "/", Data: float64(1)}, ev)
+
+	ev = <-notifications
+	assert.Equal(t, Event{Type: "patch", Path: "/a", Data: float64(2)}, ev)
+
+	fb.StopWatching()
+
+	_, ok := <-notifications
+	assert.False(t, ok)
+}
+
+func TestWatch_ServerCancel(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, "event: cancel\ndata: null\n\n")
+		flusher.Flush()
+	}))
+	defer server.Close()
+
+	fb := New(server.URL, nil)
+	notifications := make(chan Event)
+	require.NoError(t, fb.Watch(notifications))
+
+	ev := <-notifications
+	assert.Equal(t, "cancel", ev.Type)
+	assert.IsType(t, ErrWatchCancelled{}, ev.Err)
+
+	_, ok := <-notifications
+	assert.False(t, ok)
+}